@@ -1,76 +1,106 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/stolostron/automation-capi/tests/pkg/diagnostics"
+	"github.com/stolostron/automation-capi/tests/pkg/monitor"
+	"github.com/stolostron/automation-capi/tests/pkg/suiteconfig"
 	"github.com/stolostron/automation-capi/tests/pkg/validators"
 )
 
-// TestReport contains the overall test results
+// TestReport contains the overall test results for a single cluster
 type TestReport struct {
-	Environment   string                        `json:"environment"`
-	TestSuite     string                        `json:"test_suite"`
-	StartTime     time.Time                     `json:"start_time"`
-	EndTime       time.Time                     `json:"end_time"`
-	Duration      string                        `json:"duration"`
-	TotalTests    int                           `json:"total_tests"`
-	PassedTests   int                           `json:"passed_tests"`
-	FailedTests   int                           `json:"failed_tests"`
-	Results       []validators.ValidationResult `json:"results"`
-	Status        string                        `json:"status"`
+	Environment string                        `json:"environment"`
+	TestSuite   string                        `json:"test_suite"`
+	StartTime   time.Time                     `json:"start_time"`
+	EndTime     time.Time                     `json:"end_time"`
+	Duration    string                        `json:"duration"`
+	TotalTests  int                           `json:"total_tests"`
+	PassedTests int                           `json:"passed_tests"`
+	FailedTests int                           `json:"failed_tests"`
+	Results     []validators.ValidationResult `json:"results"`
+	Status      string                        `json:"status"`
 }
 
-func main() {
-	kubeconfigPath := flag.String("kubeconfig", "", "Path to kubeconfig file (defaults to $HOME/.kube/config)")
-	outputPath := flag.String("output", "", "Path to save JSON test results (optional)")
-	testSuite := flag.String("suite", "capi-installation", "Test suite name")
-	environment := flag.String("env", "unknown", "Environment type (minikube, openshift)")
+// MultiClusterReport groups a suite run's TestReports by kubeconfig
+// context, so hub/spoke (ACM-style) validation can be reported in one JSON
+// artifact instead of one file per cluster.
+type MultiClusterReport struct {
+	Environment string                `json:"environment"`
+	TestSuite   string                `json:"test_suite"`
+	StartTime   time.Time             `json:"start_time"`
+	EndTime     time.Time             `json:"end_time"`
+	Duration    string                `json:"duration"`
+	Clusters    map[string]TestReport `json:"clusters"`
+	Status      string                `json:"status"`
+}
 
-	flag.Parse()
+// stringList collects a repeated flag (--tag, --context) into a []string.
+type stringList []string
 
-	// Default kubeconfig path
-	if *kubeconfigPath == "" {
-		home, err := os.UserHomeDir()
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// buildRegistry returns the registry a suite run selects validators from:
+// the default hard-coded set, or the one described by suiteCfg's
+// validators when suiteCfg is non-nil.
+func buildRegistry(validator *validators.CAPIValidator, suiteCfg *suiteconfig.Config) (*validators.Registry, error) {
+	if suiteCfg == nil {
+		return validators.DefaultRegistry(validator), nil
+	}
+
+	registry := validators.NewRegistry()
+	for _, v := range suiteCfg.Validators {
+		built, err := validators.NewValidatorFromConfig(validator, v.Type, v.Namespace, v.Name, v.Tags, v.ExpectedReplicas)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("suite config %s: %w", suiteCfg.SuiteName, err)
 		}
-		*kubeconfigPath = filepath.Join(home, ".kube", "config")
+		registry.Register(built)
 	}
+	return registry, nil
+}
 
-	fmt.Printf("🧪 CAPI/CAPA Test Framework\n")
-	fmt.Printf("Environment: %s\n", *environment)
-	fmt.Printf("Test Suite: %s\n", *testSuite)
-	fmt.Printf("Kubeconfig: %s\n\n", *kubeconfigPath)
+// runCluster builds a validator and registry for contextName and runs the
+// suite against it, returning the resulting TestReport. When any check
+// fails, it also collects pod logs, events, and workload YAML for the
+// failing namespaces under diagnosticsDir.
+func runCluster(kubeconfigPath, contextName string, suiteFile *suiteconfig.Config, testSuite, environment, diagnosticsDir string, diagnosticsTar bool, suiteCfg validators.SuiteConfig) (TestReport, error) {
+	validator, err := validators.NewCAPIValidator(kubeconfigPath, contextName)
+	if err != nil {
+		return TestReport{}, fmt.Errorf("failed to create validator: %w", err)
+	}
 
-	// Create validator
-	validator, err := validators.NewCAPIValidator(*kubeconfigPath)
+	registry, err := buildRegistry(validator, suiteFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to create validator: %v\n", err)
-		os.Exit(1)
+		return TestReport{}, fmt.Errorf("failed to build validator registry: %w", err)
 	}
 
-	// Initialize test report
 	report := TestReport{
-		Environment: *environment,
-		TestSuite:   *testSuite,
+		Environment: environment,
+		TestSuite:   testSuite,
 		StartTime:   time.Now(),
 	}
 
-	// Run all validations
-	fmt.Println("Running validations...\n")
-	results := validator.RunAllValidations()
+	results := validators.RunSuite(suiteCfg, registry)
 	report.Results = results
 	report.EndTime = time.Now()
 	report.Duration = report.EndTime.Sub(report.StartTime).String()
 	report.TotalTests = len(results)
 
-	// Print results
 	for _, result := range results {
 		status := "✅"
 		if !result.Passed {
@@ -82,46 +112,227 @@ func main() {
 		fmt.Printf("%s %s: %s (duration: %s)\n", status, result.Name, result.Message, result.Duration)
 	}
 
-	// Determine overall status
 	if report.FailedTests == 0 {
 		report.Status = "PASSED"
 	} else {
 		report.Status = "FAILED"
 	}
 
-	// Print summary
-	fmt.Printf("\n" + "═"*60 + "\n")
-	fmt.Printf("Test Summary\n")
-	fmt.Printf("═"*60 + "\n")
-	fmt.Printf("Environment:   %s\n", report.Environment)
-	fmt.Printf("Test Suite:    %s\n", report.TestSuite)
-	fmt.Printf("Total Tests:   %d\n", report.TotalTests)
-	fmt.Printf("Passed:        %d\n", report.PassedTests)
-	fmt.Printf("Failed:        %d\n", report.FailedTests)
-	fmt.Printf("Duration:      %s\n", report.Duration)
-	fmt.Printf("Status:        %s\n", report.Status)
-	fmt.Printf("═"*60 + "\n")
-
-	// Save results to file if output path specified
+	if report.FailedTests > 0 {
+		diagDir, err := diagnostics.Collect(validator.Context(), validator.Clientset(), diagnosticsDir, toDiagnosticsResults(results), diagnosticsTar)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to collect diagnostics: %v\n", err)
+		} else if diagDir != "" {
+			fmt.Printf("🩺 Diagnostics saved to: %s\n", diagDir)
+		}
+	}
+
+	return report, nil
+}
+
+// toDiagnosticsResults adapts []validators.ValidationResult to the minimal
+// shape pkg/diagnostics needs, without pkg/diagnostics importing
+// pkg/validators.
+func toDiagnosticsResults(results []validators.ValidationResult) []diagnostics.ValidationResult {
+	out := make([]diagnostics.ValidationResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, diagnostics.ValidationResult{Passed: r.Passed, Namespace: r.Namespace})
+	}
+	return out
+}
+
+func main() {
+	kubeconfigPath := flag.String("kubeconfig", "", "Path to kubeconfig file (defaults to $HOME/.kube/config if it exists, otherwise falls back to in-cluster config)")
+	outputPath := flag.String("output", "", "Path to save JSON test results (optional)")
+	junitPath := flag.String("junit-output", "", "Path to save JUnit XML test results (optional)")
+	testSuite := flag.String("suite", "capi-installation", "Test suite name")
+	environment := flag.String("env", "unknown", "Environment type (minikube, openshift)")
+	eventuallyTimeout := flag.Duration("eventually-timeout", validators.DefaultSuiteConfig().EventuallyTimeout,
+		"How long each check waits for a CAPI/CAPA deployment to become ready")
+	pollInterval := flag.Duration("poll-interval", validators.DefaultSuiteConfig().PollInterval,
+		"How often each check re-polls the API server while waiting")
+	suiteConfigPath := flag.String("suite-config", "", "Path to a YAML suite file describing which validators to run (optional)")
+	var tags stringList
+	flag.Var(&tags, "tag", "Only run validators carrying this tag (may be repeated; default: all)")
+	var contexts stringList
+	flag.Var(&contexts, "context", "Kubeconfig context to validate (may be repeated to validate multiple clusters, e.g. hub + managed)")
+	serveAddr := flag.String("serve", "", "Run continuously, serving Prometheus metrics on this address (e.g. :8080) instead of exiting after one run; uses the first --context")
+	serveInterval := flag.Duration("serve-interval", 5*time.Minute, "How often to re-run the suite in --serve mode")
+	diagnosticsDir := flag.String("diagnostics-dir", ".", "Directory to write a diagnostics/ subdirectory (pod logs, events, workload YAML) into when a validation fails")
+	diagnosticsTar := flag.Bool("diagnostics-tar", false, "Also archive each failing namespace's diagnostics into a .tar.gz")
+
+	flag.Parse()
+
+	// Default kubeconfig path, but only if it actually exists: an absent
+	// path is left empty so NewCAPIValidator falls back to in-cluster
+	// config instead of failing on a missing file.
+	if *kubeconfigPath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if candidate := filepath.Join(home, ".kube", "config"); fileExists(candidate) {
+				*kubeconfigPath = candidate
+			}
+		}
+	}
+
+	fmt.Printf("🧪 CAPI/CAPA Test Framework\n")
+	fmt.Printf("Environment: %s\n", *environment)
+	fmt.Printf("Test Suite: %s\n", *testSuite)
+	fmt.Printf("Kubeconfig: %s\n\n", *kubeconfigPath)
+
+	var suiteFile *suiteconfig.Config
+	if *suiteConfigPath != "" {
+		var err error
+		suiteFile, err = suiteconfig.Load(*suiteConfigPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// An explicit --eventually-timeout/--poll-interval always wins; a
+	// --suite-config file's timeouts: block only applies when the CLI flag
+	// was left at its default.
+	flagSet := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { flagSet[f.Name] = true })
+
+	suiteCfg := validators.SuiteConfig{
+		EventuallyTimeout: *eventuallyTimeout,
+		PollInterval:      *pollInterval,
+		JUnitPath:         *junitPath,
+		Tags:              tags,
+	}
+	if suiteFile != nil {
+		if !flagSet["eventually-timeout"] {
+			if d, err := suiteFile.EventuallyTimeout(suiteCfg.EventuallyTimeout); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			} else {
+				suiteCfg.EventuallyTimeout = d
+			}
+		}
+		if !flagSet["poll-interval"] {
+			if d, err := suiteFile.PollInterval(suiteCfg.PollInterval); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			} else {
+				suiteCfg.PollInterval = d
+			}
+		}
+	}
+
+	if len(contexts) == 0 {
+		contexts = stringList{""}
+	}
+
+	// --serve turns the binary into a long-running blackbox monitor: it
+	// re-runs the suite on an interval and serves the results as
+	// Prometheus metrics instead of exiting after one run. Multi-cluster
+	// mode isn't supported here, so only the first --context is used.
+	if *serveAddr != "" {
+		validator, err := validators.NewCAPIValidator(*kubeconfigPath, contexts[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to create validator: %v\n", err)
+			os.Exit(1)
+		}
+		registry, err := buildRegistry(validator, suiteFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to build validator registry: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📡 Serving metrics on %s (re-running every %s)\n", *serveAddr, *serveInterval)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		m := monitor.New(registry, suiteCfg, *serveInterval)
+		if err := m.Serve(ctx, *serveAddr); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "❌ Monitor server failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	clusters := map[string]TestReport{}
+	var labels []string
+	overallFailed := false
+	for _, contextName := range contexts {
+		label := contextName
+		if label == "" {
+			label = "default"
+		}
+		labels = append(labels, label)
+
+		fmt.Printf("Running validations against context %q...\n\n", label)
+		report, err := runCluster(*kubeconfigPath, contextName, suiteFile, *testSuite, *environment, *diagnosticsDir, *diagnosticsTar, suiteCfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		clusters[label] = report
+		if report.Status != "PASSED" {
+			overallFailed = true
+		}
+
+		fmt.Printf("\n" + strings.Repeat("═", 60) + "\n")
+		fmt.Printf("Cluster:       %s\n", label)
+		fmt.Printf("Total Tests:   %d\n", report.TotalTests)
+		fmt.Printf("Passed:        %d\n", report.PassedTests)
+		fmt.Printf("Failed:        %d\n", report.FailedTests)
+		fmt.Printf("Status:        %s\n", report.Status)
+		fmt.Printf(strings.Repeat("═", 60) + "\n\n")
+	}
+
 	if *outputPath != "" {
-		jsonData, err := json.MarshalIndent(report, "", "  ")
+		var jsonData []byte
+		var err error
+		if len(labels) == 1 {
+			// Preserve the original single-cluster JSON shape when no
+			// --context was given more than once.
+			jsonData, err = json.MarshalIndent(clusters[labels[0]], "", "  ")
+		} else {
+			multi := MultiClusterReport{
+				Environment: *environment,
+				TestSuite:   *testSuite,
+				Clusters:    clusters,
+			}
+			for _, report := range clusters {
+				if multi.StartTime.IsZero() || report.StartTime.Before(multi.StartTime) {
+					multi.StartTime = report.StartTime
+				}
+				if report.EndTime.After(multi.EndTime) {
+					multi.EndTime = report.EndTime
+				}
+			}
+			multi.Duration = multi.EndTime.Sub(multi.StartTime).String()
+			multi.Status = "PASSED"
+			if overallFailed {
+				multi.Status = "FAILED"
+			}
+			jsonData, err = json.MarshalIndent(multi, "", "  ")
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Failed to marshal results to JSON: %v\n", err)
 			os.Exit(1)
 		}
 
-		err = os.WriteFile(*outputPath, jsonData, 0644)
-		if err != nil {
+		if err := os.WriteFile(*outputPath, jsonData, 0644); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ Failed to write results to file: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("\n📄 Results saved to: %s\n", *outputPath)
+		fmt.Printf("📄 Results saved to: %s\n", *outputPath)
+	}
+
+	if *junitPath != "" {
+		fmt.Printf("📄 JUnit report saved to: %s\n", *junitPath)
 	}
 
-	// Exit with appropriate code
-	if report.Status == "FAILED" {
+	if overallFailed {
 		os.Exit(1)
 	}
 	os.Exit(0)
 }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
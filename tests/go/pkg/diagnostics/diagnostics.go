@@ -0,0 +1,231 @@
+// Package diagnostics collects pod logs, events, and workload YAML for a
+// namespace when a validation fails, mirroring the pod-log retrieval
+// pattern from ARO-RP's adminapi tests so a CAPA install failure doesn't
+// need a live cluster to triage.
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// tailLines bounds how much of each pod's log is collected.
+const tailLines = 200
+
+// Collect gathers logs, events, and Deployment/ReplicaSet/Pod YAML for
+// namespace into <dir>/diagnostics/<namespace>, for every failed result in
+// results. It's a no-op, returning "", when every result passed. When
+// tarGz is true the directory is also archived to
+// <dir>/diagnostics/<namespace>.tar.gz.
+func Collect(ctx context.Context, clientset *kubernetes.Clientset, dir string, results []ValidationResult, tarGz bool) (string, error) {
+	namespaces := failingNamespaces(results)
+	if len(namespaces) == 0 {
+		return "", nil
+	}
+
+	root := filepath.Join(dir, "diagnostics")
+	for _, namespace := range namespaces {
+		nsDir := filepath.Join(root, namespace)
+		if err := os.MkdirAll(nsDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create diagnostics directory %s: %w", nsDir, err)
+		}
+
+		if err := collectPodLogs(ctx, clientset, namespace, nsDir); err != nil {
+			return "", err
+		}
+		if err := collectEvents(ctx, clientset, namespace, nsDir); err != nil {
+			return "", err
+		}
+		if err := collectWorkloads(ctx, clientset, namespace, nsDir); err != nil {
+			return "", err
+		}
+
+		if tarGz {
+			if err := archiveDir(nsDir, nsDir+".tar.gz"); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// ValidationResult is the subset of validators.ValidationResult diagnostics
+// needs; it's defined here rather than imported to avoid a dependency
+// between the two packages.
+type ValidationResult struct {
+	Passed    bool
+	Namespace string
+}
+
+// failingNamespaces returns the distinct, non-empty namespaces of every
+// failed result.
+func failingNamespaces(results []ValidationResult) []string {
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, r := range results {
+		if r.Passed || r.Namespace == "" || seen[r.Namespace] {
+			continue
+		}
+		seen[r.Namespace] = true
+		namespaces = append(namespaces, r.Namespace)
+	}
+	return namespaces
+}
+
+// collectPodLogs writes the last tailLines of every container's log, for
+// every pod in namespace, to <dir>/<pod>-<container>.log.
+func collectPodLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, dir string) error {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	lines := int64(tailLines)
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container.Name,
+				TailLines: &lines,
+			}).Stream(ctx)
+			if err != nil {
+				// A container that hasn't started yet (or has already been
+				// evicted) shouldn't block collecting the rest.
+				continue
+			}
+
+			path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", pod.Name, container.Name))
+			if err := writeStream(path, stream); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectEvents writes namespace's Events, sorted by LastTimestamp, to
+// <dir>/events.yaml.
+func collectEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace, dir string) error {
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+
+	return writeYAML(filepath.Join(dir, "events.yaml"), events)
+}
+
+// collectWorkloads writes namespace's Deployments, ReplicaSets, and Pods
+// to <dir>/workloads.yaml.
+func collectWorkloads(ctx context.Context, clientset *kubernetes.Clientset, namespace, dir string) error {
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+	}
+	replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list replicasets in namespace %s: %w", namespace, err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	workloads := struct {
+		Deployments interface{} `json:"deployments"`
+		ReplicaSets interface{} `json:"replicaSets"`
+		Pods        interface{} `json:"pods"`
+	}{
+		Deployments: deployments.Items,
+		ReplicaSets: replicaSets.Items,
+		Pods:        pods.Items,
+	}
+
+	return writeYAML(filepath.Join(dir, "workloads.yaml"), workloads)
+}
+
+func writeYAML(path string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeStream(path string, stream io.ReadCloser) error {
+	defer stream.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, stream); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// archiveDir tars and gzips every file directly under dir into dest.
+func archiveDir(dir, dest string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read diagnostics directory %s: %w", dir, err)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name: entry.Name(),
+			Mode: int64(info.Mode()),
+			Size: info.Size(),
+		}); err != nil {
+			return fmt.Errorf("failed to write tar header for %s: %w", entry.Name(), err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s to archive: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFailingNamespaces(t *testing.T) {
+	results := []ValidationResult{
+		{Passed: true, Namespace: "capi-system"},
+		{Passed: false, Namespace: "capa-system"},
+		{Passed: false, Namespace: ""},
+		{Passed: false, Namespace: "capa-system"},
+		{Passed: false, Namespace: "ns-rosa-hcp"},
+	}
+
+	got := failingNamespaces(results)
+	want := []string{"capa-system", "ns-rosa-hcp"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("failingNamespaces() = %v, want %v", got, want)
+	}
+}
+
+func TestFailingNamespacesAllPassed(t *testing.T) {
+	results := []ValidationResult{
+		{Passed: true, Namespace: "capi-system"},
+		{Passed: true, Namespace: "capa-system"},
+	}
+
+	if got := failingNamespaces(results); got != nil {
+		t.Errorf("failingNamespaces() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package k8sutil provides retry-aware helpers for talking to the API
+// server. They exist because a freshly-installed CAPI/CAPA control plane
+// can return transient errors (conflicts, timeouts, 5xx) while its
+// webhooks and controllers are still coming up.
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+// RetryConfig bounds how long Retry and the GetK8sObjectWithRetry family
+// will keep retrying a transient API error before giving up.
+type RetryConfig struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// DefaultRetryConfig is used by callers that don't need a tighter or looser
+// retry budget than the suite's default polling cadence.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{Timeout: 2 * time.Minute, Interval: 5 * time.Second}
+}
+
+// isTransient reports whether err is the kind of API error a retry is
+// likely to resolve: conflicts, timeouts, and 5xx-class server errors.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	return apierrors.IsConflict(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsTooManyRequests(err)
+}
+
+// Retry runs fn, retrying while it returns a transient API error, until
+// cfg.Timeout elapses. It underlies every helper in this package, and is
+// exported directly for call sites (typed clientset Gets, in particular)
+// that don't go through a dynamic.ResourceInterface.
+func Retry(ctx context.Context, cfg RetryConfig, fn func(ctx context.Context) error) error {
+	return wait.PollUntilContextTimeout(ctx, cfg.Interval, cfg.Timeout, true, func(ctx context.Context) (bool, error) {
+		err := fn(ctx)
+		if err == nil {
+			return true, nil
+		}
+		if isTransient(err) {
+			return false, nil
+		}
+		return false, err
+	})
+}
+
+// GetK8sObjectWithRetry fetches name from res, retrying transient API
+// errors until cfg.Timeout elapses.
+func GetK8sObjectWithRetry(ctx context.Context, res dynamic.ResourceInterface, name string, cfg RetryConfig) (*unstructured.Unstructured, error) {
+	var obj *unstructured.Unstructured
+	err := Retry(ctx, cfg, func(ctx context.Context) error {
+		var getErr error
+		obj, getErr = res.Get(ctx, name, metav1.GetOptions{})
+		return getErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s after retries: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListK8sObjectsWithRetry lists res, retrying transient API errors until
+// cfg.Timeout elapses.
+func ListK8sObjectsWithRetry(ctx context.Context, res dynamic.ResourceInterface, opts metav1.ListOptions, cfg RetryConfig) (*unstructured.UnstructuredList, error) {
+	var list *unstructured.UnstructuredList
+	err := Retry(ctx, cfg, func(ctx context.Context) error {
+		var listErr error
+		list, listErr = res.List(ctx, opts)
+		return listErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list after retries: %w", err)
+	}
+	return list, nil
+}
+
+// CreateK8sObjectWithRetry creates obj via res, retrying transient API
+// errors until cfg.Timeout elapses.
+func CreateK8sObjectWithRetry(ctx context.Context, res dynamic.ResourceInterface, obj *unstructured.Unstructured, cfg RetryConfig) (*unstructured.Unstructured, error) {
+	var created *unstructured.Unstructured
+	err := Retry(ctx, cfg, func(ctx context.Context) error {
+		var createErr error
+		created, createErr = res.Create(ctx, obj, metav1.CreateOptions{})
+		return createErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s after retries: %w", obj.GetName(), err)
+	}
+	return created, nil
+}
+
+// DeleteK8sObjectWithRetry deletes name via res, retrying transient API
+// errors until cfg.Timeout elapses. A NotFound error is treated as success
+// since the object is already gone.
+func DeleteK8sObjectWithRetry(ctx context.Context, res dynamic.ResourceInterface, name string, cfg RetryConfig) error {
+	err := Retry(ctx, cfg, func(ctx context.Context) error {
+		delErr := res.Delete(ctx, name, metav1.DeleteOptions{})
+		if delErr == nil || apierrors.IsNotFound(delErr) {
+			return nil
+		}
+		return delErr
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s after retries: %w", name, err)
+	}
+	return nil
+}
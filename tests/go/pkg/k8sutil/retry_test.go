@@ -0,0 +1,78 @@
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransient(t *testing.T) {
+	gr := schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "clusters"}
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"conflict", apierrors.NewConflict(gr, "foo", errors.New("conflict")), true},
+		{"timeout", apierrors.NewTimeoutError("timed out", 0), true},
+		{"service unavailable", apierrors.NewServiceUnavailable("unavailable"), true},
+		{"too many requests", apierrors.NewTooManyRequests("retry later", 0), true},
+		{"not found", apierrors.NewNotFound(gr, "foo"), false},
+		{"plain error", errors.New("boom"), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isTransient(c.err); got != c.want {
+				t.Errorf("isTransient(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryGivesUpOnNonTransientError(t *testing.T) {
+	calls := 0
+	want := errors.New("boom")
+	err := Retry(context.Background(), RetryConfig{Timeout: time.Second, Interval: time.Millisecond}, func(_ context.Context) error {
+		calls++
+		return want
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("Retry() error = %v, want %v", err, want)
+	}
+	if calls != 1 {
+		t.Fatalf("Retry() called fn %d times for a non-transient error, want 1", calls)
+	}
+}
+
+func TestRetryRetriesTransientErrorUntilSuccess(t *testing.T) {
+	gr := schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "clusters"}
+	calls := 0
+	err := Retry(context.Background(), RetryConfig{Timeout: time.Second, Interval: time.Millisecond}, func(_ context.Context) error {
+		calls++
+		if calls < 3 {
+			return apierrors.NewConflict(gr, "foo", errors.New("conflict"))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() error = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Fatalf("Retry() called fn %d times, want 3", calls)
+	}
+}
+
+func TestRetryTimesOutOnPersistentTransientError(t *testing.T) {
+	gr := schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "clusters"}
+	err := Retry(context.Background(), RetryConfig{Timeout: 20 * time.Millisecond, Interval: 5 * time.Millisecond}, func(_ context.Context) error {
+		return apierrors.NewConflict(gr, "foo", errors.New("conflict"))
+	})
+	if err == nil {
+		t.Fatal("Retry() error = nil, want a timeout error")
+	}
+}
@@ -0,0 +1,115 @@
+// Package monitor turns a validators.Registry into a long-running
+// blackbox monitor: it re-runs the suite on an interval and exposes the
+// results as Prometheus metrics, alongside /healthz and /readyz endpoints,
+// for watching CAPI/CAPA controller health in long-running clusters.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/stolostron/automation-capi/tests/pkg/validators"
+)
+
+// Monitor periodically re-runs a validators.Registry and serves the
+// results as Prometheus metrics.
+type Monitor struct {
+	registry *validators.Registry
+	suite    validators.SuiteConfig
+	interval time.Duration
+
+	passed   *prometheus.GaugeVec
+	duration *prometheus.GaugeVec
+	lastRun  prometheus.Gauge
+
+	ready atomic.Bool
+}
+
+// New returns a Monitor that re-runs registry's validators, per suite,
+// every interval.
+func New(registry *validators.Registry, suite validators.SuiteConfig, interval time.Duration) *Monitor {
+	m := &Monitor{
+		registry: registry,
+		suite:    suite,
+		interval: interval,
+		passed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "capi_validation_passed",
+			Help: "Whether a CAPI validation check last passed (1) or failed (0).",
+		}, []string{"name"}),
+		duration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "capi_validation_duration_seconds",
+			Help: "Duration of the last run of a CAPI validation check, in seconds.",
+		}, []string{"name"}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "capi_validation_last_run_timestamp",
+			Help: "Unix timestamp of the last completed validation run.",
+		}),
+	}
+	prometheus.MustRegister(m.passed, m.duration, m.lastRun)
+	return m
+}
+
+// Serve runs the validation loop in the background and serves /metrics,
+// /healthz and /readyz on addr until ctx is done.
+func (m *Monitor) Serve(ctx context.Context, addr string) error {
+	go m.loop(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if !m.ready.Load() {
+			http.Error(w, "waiting for first validation run", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	return server.ListenAndServe()
+}
+
+// loop re-runs the suite every m.interval until ctx is done, recording
+// each run's results as Prometheus metrics.
+func (m *Monitor) loop(ctx context.Context) {
+	m.runOnce()
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.runOnce()
+		}
+	}
+}
+
+func (m *Monitor) runOnce() {
+	results := validators.RunSuite(m.suite, m.registry)
+	for _, r := range results {
+		passedValue := 0.0
+		if r.Passed {
+			passedValue = 1.0
+		}
+		m.passed.WithLabelValues(r.Name).Set(passedValue)
+
+		if d, err := time.ParseDuration(r.Duration); err == nil {
+			m.duration.WithLabelValues(r.Name).Set(d.Seconds())
+		}
+	}
+	m.lastRun.Set(float64(time.Now().Unix()))
+	m.ready.Store(true)
+}
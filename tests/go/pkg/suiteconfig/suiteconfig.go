@@ -0,0 +1,76 @@
+// Package suiteconfig loads the YAML file a --suite-config flag points at:
+// which validators a suite run should register, their parameters, and the
+// tags used to filter them (--tag smoke, --tag rosa, ...).
+package suiteconfig
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the on-disk shape of a --suite-config file.
+type Config struct {
+	SuiteName  string            `yaml:"suiteName"`
+	Validators []ValidatorConfig `yaml:"validators"`
+	Timeouts   TimeoutsConfig    `yaml:"timeouts"`
+}
+
+// ValidatorConfig describes one validator to register: its type and the
+// parameters that type needs.
+type ValidatorConfig struct {
+	Type      string   `yaml:"type"`
+	Namespace string   `yaml:"namespace,omitempty"`
+	Name      string   `yaml:"name,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	// ExpectedReplicas overrides how many ready replicas a capi-system or
+	// capa-system validator requires, for environments that don't run the
+	// controller manager's full default replica count. Zero means "use
+	// the Deployment's own status.replicas", the prior hard-coded behavior.
+	ExpectedReplicas int32 `yaml:"expectedReplicas,omitempty"`
+}
+
+// TimeoutsConfig overrides the suite's default Eventually polling. Values
+// are parsed with time.ParseDuration (e.g. "5m", "5s").
+type TimeoutsConfig struct {
+	Eventually string `yaml:"eventually,omitempty"`
+	Poll       string `yaml:"poll,omitempty"`
+}
+
+// Load reads and parses a --suite-config file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse suite config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// EventuallyTimeout parses Timeouts.Eventually, returning fallback when
+// it's unset.
+func (c *Config) EventuallyTimeout(fallback time.Duration) (time.Duration, error) {
+	return parseDurationOrDefault(c.Timeouts.Eventually, fallback)
+}
+
+// PollInterval parses Timeouts.Poll, returning fallback when it's unset.
+func (c *Config) PollInterval(fallback time.Duration) (time.Duration, error) {
+	return parseDurationOrDefault(c.Timeouts.Poll, fallback)
+}
+
+func parseDurationOrDefault(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	return d, nil
+}
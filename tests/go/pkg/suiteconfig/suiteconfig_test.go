@@ -0,0 +1,88 @@
+package suiteconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSuiteFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeSuiteFile(t, `
+suiteName: rosa-hcp
+validators:
+  - type: namespace
+    namespace: ns-rosa-hcp
+    tags: [rosa]
+  - type: capi-system
+    expectedReplicas: 2
+timeouts:
+  eventually: 10m
+  poll: 15s
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.SuiteName != "rosa-hcp" {
+		t.Errorf("SuiteName = %q, want rosa-hcp", cfg.SuiteName)
+	}
+	if len(cfg.Validators) != 2 {
+		t.Fatalf("len(Validators) = %d, want 2", len(cfg.Validators))
+	}
+	if cfg.Validators[1].ExpectedReplicas != 2 {
+		t.Errorf("Validators[1].ExpectedReplicas = %d, want 2", cfg.Validators[1].ExpectedReplicas)
+	}
+
+	eventually, err := cfg.EventuallyTimeout(5 * time.Minute)
+	if err != nil {
+		t.Fatalf("EventuallyTimeout() error = %v", err)
+	}
+	if eventually != 10*time.Minute {
+		t.Errorf("EventuallyTimeout() = %v, want 10m", eventually)
+	}
+
+	poll, err := cfg.PollInterval(5 * time.Second)
+	if err != nil {
+		t.Fatalf("PollInterval() error = %v", err)
+	}
+	if poll != 15*time.Second {
+		t.Errorf("PollInterval() = %v, want 15s", poll)
+	}
+}
+
+func TestConfigTimeoutsFallBackWhenUnset(t *testing.T) {
+	cfg := &Config{}
+
+	eventually, err := cfg.EventuallyTimeout(5 * time.Minute)
+	if err != nil {
+		t.Fatalf("EventuallyTimeout() error = %v", err)
+	}
+	if eventually != 5*time.Minute {
+		t.Errorf("EventuallyTimeout() = %v, want fallback 5m", eventually)
+	}
+}
+
+func TestConfigTimeoutsRejectInvalidDuration(t *testing.T) {
+	cfg := &Config{Timeouts: TimeoutsConfig{Eventually: "not-a-duration"}}
+
+	if _, err := cfg.EventuallyTimeout(5 * time.Minute); err == nil {
+		t.Fatal("EventuallyTimeout() error = nil, want an error for an invalid duration")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("Load() error = nil, want an error for a missing file")
+	}
+}
@@ -0,0 +1,149 @@
+package validators
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/stolostron/automation-capi/tests/pkg/k8sutil"
+)
+
+// capiResource describes a Cluster API (or provider) CRD that
+// ValidateCAPIResources walks, identified by its GroupVersionResource, and
+// the condition types that must be True for an instance to be healthy.
+// conditions reuses clusterv1.ConditionType, the same type the upstream
+// CAPI controllers set on status.conditions[].type, rather than hand-rolled
+// string literals.
+type capiResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	conditions []clusterv1.ConditionType
+}
+
+// defaultCAPIResources are the CRDs ValidateCAPIResources inspects. Their
+// GVRs match sigs.k8s.io/cluster-api and
+// sigs.k8s.io/cluster-api-provider-aws/v2, already required by go.mod.
+func defaultCAPIResources() []capiResource {
+	return []capiResource{
+		{
+			gvr:        schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "clusters"},
+			kind:       "Cluster",
+			conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition, clusterv1.ControlPlaneReadyCondition, clusterv1.InfrastructureReadyCondition},
+		},
+		{
+			gvr:        schema.GroupVersionResource{Group: "cluster.x-k8s.io", Version: "v1beta1", Resource: "machinedeployments"},
+			kind:       "MachineDeployment",
+			conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition},
+		},
+		{
+			gvr:        schema.GroupVersionResource{Group: "controlplane.cluster.x-k8s.io", Version: "v1beta2", Resource: "awsmanagedcontrolplanes"},
+			kind:       "AWSManagedControlPlane",
+			conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition, clusterv1.ControlPlaneReadyCondition},
+		},
+		{
+			gvr:        schema.GroupVersionResource{Group: "controlplane.cluster.x-k8s.io", Version: "v1beta2", Resource: "rosacontrolplanes"},
+			kind:       "ROSAControlPlane",
+			conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition, clusterv1.ControlPlaneReadyCondition},
+		},
+		{
+			gvr:        schema.GroupVersionResource{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2", Resource: "rosamachinepools"},
+			kind:       "ROSAMachinePool",
+			conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition},
+		},
+	}
+}
+
+// ValidateCAPIResources walks the Cluster API CRDs in defaultCAPIResources
+// for every object found in namespace, evaluating status.conditions and
+// surfacing any non-Ready condition as a failed ValidationResult. A CRD
+// with no instances in namespace is reported as passing.
+func (v *CAPIValidator) ValidateCAPIResources(namespace string) []ValidationResult {
+	var results []ValidationResult
+	for _, r := range defaultCAPIResources() {
+		results = append(results, v.validateResource(r, namespace)...)
+	}
+	return results
+}
+
+func (v *CAPIValidator) validateResource(r capiResource, namespace string) []ValidationResult {
+	start := time.Now()
+
+	list, err := k8sutil.ListK8sObjectsWithRetry(v.ctx, v.dynamicClient.Resource(r.gvr).Namespace(namespace), metav1.ListOptions{}, k8sutil.DefaultRetryConfig())
+	if err != nil {
+		return []ValidationResult{{
+			Name:      r.kind,
+			Passed:    false,
+			Message:   fmt.Sprintf("failed to list %s in namespace %s: %v", r.kind, namespace, err),
+			Timestamp: start,
+			Duration:  time.Since(start).String(),
+			Namespace: namespace,
+		}}
+	}
+
+	if len(list.Items) == 0 {
+		return []ValidationResult{{
+			Name:      r.kind,
+			Passed:    true,
+			Message:   fmt.Sprintf("no %s found in namespace %s", r.kind, namespace),
+			Timestamp: start,
+			Duration:  time.Since(start).String(),
+			Namespace: namespace,
+		}}
+	}
+
+	results := make([]ValidationResult, 0, len(list.Items))
+	for _, obj := range list.Items {
+		result := validateConditions(r, obj, start)
+		result.Namespace = namespace
+		results = append(results, result)
+	}
+	return results
+}
+
+// validateConditions evaluates obj's status.conditions against
+// r.conditions, failing the result on the first condition that isn't True.
+func validateConditions(r capiResource, obj unstructured.Unstructured, start time.Time) ValidationResult {
+	name := fmt.Sprintf("%s/%s", r.kind, obj.GetName())
+	result := ValidationResult{Name: name, Timestamp: start}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		result.Passed = false
+		result.Message = fmt.Sprintf("%s has no status.conditions", name)
+		result.Duration = time.Since(start).String()
+		return result
+	}
+
+	status := map[clusterv1.ConditionType]string{}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := condition["type"].(string)
+		condStatus, _ := condition["status"].(string)
+		status[clusterv1.ConditionType(condType)] = condStatus
+	}
+
+	for _, want := range r.conditions {
+		got, ok := status[want]
+		if !ok {
+			got = "Unknown"
+		}
+		if got != "True" {
+			result.Passed = false
+			result.Message = fmt.Sprintf("%s condition %s is %s", name, want, got)
+			result.Duration = time.Since(start).String()
+			return result
+		}
+	}
+
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s is Ready", name)
+	result.Duration = time.Since(start).String()
+	return result
+}
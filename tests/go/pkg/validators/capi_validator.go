@@ -8,8 +8,12 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/stolostron/automation-capi/tests/pkg/k8sutil"
 )
 
 // ValidationResult represents the outcome of a validation check
@@ -19,19 +23,28 @@ type ValidationResult struct {
 	Message   string    `json:"message"`
 	Timestamp time.Time `json:"timestamp"`
 	Duration  string    `json:"duration"`
+	// Namespace is the namespace the check was run against, when it
+	// applies to one. It lets the failure-hook in pkg/diagnostics know
+	// where to collect pod logs and events from.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // CAPIValidator handles validation of CAPI installations
 type CAPIValidator struct {
-	clientset *kubernetes.Clientset
-	ctx       context.Context
+	clientset     *kubernetes.Clientset
+	dynamicClient dynamic.Interface
+	ctx           context.Context
 }
 
-// NewCAPIValidator creates a new CAPI validator using kubeconfig
-func NewCAPIValidator(kubeconfigPath string) (*CAPIValidator, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// NewCAPIValidator creates a new CAPI validator for contextName within
+// kubeconfigPath. When kubeconfigPath is empty it falls back to
+// rest.InClusterConfig(), mirroring how Helm's configForContext and the
+// cert-agent controller resolve their REST config when running in-cluster.
+// contextName is ignored in that case.
+func NewCAPIValidator(kubeconfigPath, contextName string) (*CAPIValidator, error) {
+	config, err := restConfigForContext(kubeconfigPath, contextName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+		return nil, err
 	}
 
 	clientset, err := kubernetes.NewForConfig(config)
@@ -39,22 +52,99 @@ func NewCAPIValidator(kubeconfigPath string) (*CAPIValidator, error) {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
 	return &CAPIValidator{
-		clientset: clientset,
-		ctx:       context.Background(),
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		ctx:           context.Background(),
 	}, nil
 }
 
-// ValidateCAPISystem checks if CAPI controller manager is running
-func (v *CAPIValidator) ValidateCAPISystem() ValidationResult {
+// Clientset exposes the validator's typed client, for callers (e.g. the
+// diagnostics failure-hook) that need to reach the API server directly.
+func (v *CAPIValidator) Clientset() *kubernetes.Clientset {
+	return v.clientset
+}
+
+// Context exposes the validator's context, for callers (e.g. the
+// diagnostics failure-hook) that need to reach the API server directly.
+func (v *CAPIValidator) Context() context.Context {
+	return v.ctx
+}
+
+// restConfigForContext builds a REST config for contextName within
+// kubeconfigPath, or falls back to the in-cluster service account config
+// when kubeconfigPath is empty.
+func restConfigForContext(kubeconfigPath, contextName string) (*rest.Config, error) {
+	if kubeconfigPath == "" {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("no kubeconfig supplied and not running in-cluster: %w", err)
+		}
+		return config, nil
+	}
+
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig for context %q: %w", contextName, err)
+	}
+	return config, nil
+}
+
+// getNamespace fetches name, retrying transient API errors so a
+// freshly-installed control plane's webhooks coming up doesn't fail a
+// check outright.
+func (v *CAPIValidator) getNamespace(name string) (*corev1.Namespace, error) {
+	var ns *corev1.Namespace
+	err := k8sutil.Retry(v.ctx, k8sutil.DefaultRetryConfig(), func(ctx context.Context) error {
+		var getErr error
+		ns, getErr = v.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		return getErr
+	})
+	return ns, err
+}
+
+// getDeployment fetches name in namespace, retrying transient API errors.
+func (v *CAPIValidator) getDeployment(namespace, name string) (*appsv1.Deployment, error) {
+	var deployment *appsv1.Deployment
+	err := k8sutil.Retry(v.ctx, k8sutil.DefaultRetryConfig(), func(ctx context.Context) error {
+		var getErr error
+		deployment, getErr = v.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		return getErr
+	})
+	return deployment, err
+}
+
+// getSecret fetches name in namespace, retrying transient API errors.
+func (v *CAPIValidator) getSecret(namespace, name string) (*corev1.Secret, error) {
+	var secret *corev1.Secret
+	err := k8sutil.Retry(v.ctx, k8sutil.DefaultRetryConfig(), func(ctx context.Context) error {
+		var getErr error
+		secret, getErr = v.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		return getErr
+	})
+	return secret, err
+}
+
+// ValidateCAPISystem checks if CAPI controller manager is running.
+// expectedReplicas overrides how many ready replicas are required; zero
+// means "require the deployment's own status.replicas".
+func (v *CAPIValidator) ValidateCAPISystem(expectedReplicas int32) ValidationResult {
 	start := time.Now()
 	result := ValidationResult{
 		Name:      "CAPI Controller Manager",
 		Timestamp: start,
+		Namespace: "capi-system",
 	}
 
 	// Check if capi-system namespace exists
-	_, err := v.clientset.CoreV1().Namespaces().Get(v.ctx, "capi-system", metav1.GetOptions{})
+	_, err := v.getNamespace("capi-system")
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capi-system namespace not found: %v", err)
@@ -63,11 +153,7 @@ func (v *CAPIValidator) ValidateCAPISystem() ValidationResult {
 	}
 
 	// Check if capi-controller-manager deployment exists and is ready
-	deployment, err := v.clientset.AppsV1().Deployments("capi-system").Get(
-		v.ctx,
-		"capi-controller-manager",
-		metav1.GetOptions{},
-	)
+	deployment, err := v.getDeployment("capi-system", "capi-controller-manager")
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capi-controller-manager deployment not found: %v", err)
@@ -75,7 +161,7 @@ func (v *CAPIValidator) ValidateCAPISystem() ValidationResult {
 		return result
 	}
 
-	if !isDeploymentReady(deployment) {
+	if !isDeploymentReady(deployment, expectedReplicas) {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capi-controller-manager not ready: %d/%d replicas available",
 			deployment.Status.AvailableReplicas,
@@ -92,16 +178,19 @@ func (v *CAPIValidator) ValidateCAPISystem() ValidationResult {
 	return result
 }
 
-// ValidateCAPASystem checks if CAPA controller manager is running
-func (v *CAPIValidator) ValidateCAPASystem() ValidationResult {
+// ValidateCAPASystem checks if CAPA controller manager is running.
+// expectedReplicas overrides how many ready replicas are required; zero
+// means "require the deployment's own status.replicas".
+func (v *CAPIValidator) ValidateCAPASystem(expectedReplicas int32) ValidationResult {
 	start := time.Now()
 	result := ValidationResult{
 		Name:      "CAPA Controller Manager",
 		Timestamp: start,
+		Namespace: "capa-system",
 	}
 
 	// Check if capa-system namespace exists
-	_, err := v.clientset.CoreV1().Namespaces().Get(v.ctx, "capa-system", metav1.GetOptions{})
+	_, err := v.getNamespace("capa-system")
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capa-system namespace not found: %v", err)
@@ -110,11 +199,7 @@ func (v *CAPIValidator) ValidateCAPASystem() ValidationResult {
 	}
 
 	// Check if capa-controller-manager deployment exists and is ready
-	deployment, err := v.clientset.AppsV1().Deployments("capa-system").Get(
-		v.ctx,
-		"capa-controller-manager",
-		metav1.GetOptions{},
-	)
+	deployment, err := v.getDeployment("capa-system", "capa-controller-manager")
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capa-controller-manager deployment not found: %v", err)
@@ -122,7 +207,7 @@ func (v *CAPIValidator) ValidateCAPASystem() ValidationResult {
 		return result
 	}
 
-	if !isDeploymentReady(deployment) {
+	if !isDeploymentReady(deployment, expectedReplicas) {
 		result.Passed = false
 		result.Message = fmt.Sprintf("capa-controller-manager not ready: %d/%d replicas available",
 			deployment.Status.AvailableReplicas,
@@ -145,10 +230,11 @@ func (v *CAPIValidator) ValidateCertManager() ValidationResult {
 	result := ValidationResult{
 		Name:      "cert-manager",
 		Timestamp: start,
+		Namespace: "cert-manager",
 	}
 
 	// Check if cert-manager namespace exists
-	_, err := v.clientset.CoreV1().Namespaces().Get(v.ctx, "cert-manager", metav1.GetOptions{})
+	_, err := v.getNamespace("cert-manager")
 	if err != nil {
 		result.Passed = false
 		result.Message = "cert-manager not installed (namespace not found)"
@@ -159,11 +245,7 @@ func (v *CAPIValidator) ValidateCertManager() ValidationResult {
 	// Check cert-manager deployments
 	deployments := []string{"cert-manager", "cert-manager-webhook", "cert-manager-cainjector"}
 	for _, deployName := range deployments {
-		deployment, err := v.clientset.AppsV1().Deployments("cert-manager").Get(
-			v.ctx,
-			deployName,
-			metav1.GetOptions{},
-		)
+		deployment, err := v.getDeployment("cert-manager", deployName)
 		if err != nil {
 			result.Passed = false
 			result.Message = fmt.Sprintf("%s deployment not found: %v", deployName, err)
@@ -171,7 +253,7 @@ func (v *CAPIValidator) ValidateCertManager() ValidationResult {
 			return result
 		}
 
-		if !isDeploymentReady(deployment) {
+		if !isDeploymentReady(deployment, 0) {
 			result.Passed = false
 			result.Message = fmt.Sprintf("%s not ready: %d/%d replicas available",
 				deployName,
@@ -194,9 +276,10 @@ func (v *CAPIValidator) ValidateNamespace(namespace string) ValidationResult {
 	result := ValidationResult{
 		Name:      fmt.Sprintf("Namespace: %s", namespace),
 		Timestamp: start,
+		Namespace: namespace,
 	}
 
-	ns, err := v.clientset.CoreV1().Namespaces().Get(v.ctx, namespace, metav1.GetOptions{})
+	ns, err := v.getNamespace(namespace)
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("Namespace %s not found: %v", namespace, err)
@@ -223,9 +306,10 @@ func (v *CAPIValidator) ValidateSecret(namespace, secretName string) ValidationR
 	result := ValidationResult{
 		Name:      fmt.Sprintf("Secret: %s/%s", namespace, secretName),
 		Timestamp: start,
+		Namespace: namespace,
 	}
 
-	_, err := v.clientset.CoreV1().Secrets(namespace).Get(v.ctx, secretName, metav1.GetOptions{})
+	_, err := v.getSecret(namespace, secretName)
 	if err != nil {
 		result.Passed = false
 		result.Message = fmt.Sprintf("Secret %s not found in namespace %s: %v", secretName, namespace, err)
@@ -239,26 +323,18 @@ func (v *CAPIValidator) ValidateSecret(namespace, secretName string) ValidationR
 	return result
 }
 
-// RunAllValidations runs all validation checks
-func (v *CAPIValidator) RunAllValidations() []ValidationResult {
-	results := []ValidationResult{}
-
-	// Core validations
-	results = append(results, v.ValidateCertManager())
-	results = append(results, v.ValidateCAPISystem())
-	results = append(results, v.ValidateCAPASystem())
-	results = append(results, v.ValidateNamespace("ns-rosa-hcp"))
-
-	return results
-}
-
-// Helper function to check if a deployment is ready
-func isDeploymentReady(deployment *appsv1.Deployment) bool {
-	// Check if desired replicas match available replicas
-	if deployment.Status.Replicas == 0 {
+// isDeploymentReady reports whether deployment has at least
+// expectedReplicas available and ready replicas. expectedReplicas of zero
+// falls back to deployment.Status.Replicas, requiring every replica the
+// Deployment itself expects to be ready.
+func isDeploymentReady(deployment *appsv1.Deployment, expectedReplicas int32) bool {
+	if expectedReplicas <= 0 {
+		expectedReplicas = deployment.Status.Replicas
+	}
+	if expectedReplicas == 0 {
 		return false
 	}
 
-	return deployment.Status.AvailableReplicas == deployment.Status.Replicas &&
-		deployment.Status.ReadyReplicas == deployment.Status.Replicas
+	return deployment.Status.AvailableReplicas >= expectedReplicas &&
+		deployment.Status.ReadyReplicas >= expectedReplicas
 }
@@ -0,0 +1,193 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Validator is a single pluggable check a suite run can select, replacing
+// the hard-coded list RunAllValidations used to run. It's implemented by
+// the unexported *Validator adapters below, each wrapping one of
+// CAPIValidator's checks with the parameters (namespace, secret name, ...)
+// a --suite-config entry supplies.
+type Validator interface {
+	Name() string
+	Tags() []string
+	Run(ctx context.Context) ValidationResult
+}
+
+// Registry holds the Validators a suite run selects from.
+type Registry struct {
+	validators []Validator
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds v to the registry.
+func (r *Registry) Register(v Validator) {
+	r.validators = append(r.validators, v)
+}
+
+// Filter returns the registered Validators carrying at least one of tags.
+// An empty tags returns every registered Validator.
+func (r *Registry) Filter(tags []string) []Validator {
+	if len(tags) == 0 {
+		return r.validators
+	}
+	var matched []Validator
+	for _, v := range r.validators {
+		if hasAnyTag(v.Tags(), tags) {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+func hasAnyTag(have, want []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewValidatorFromConfig builds a Validator of validatorType, parameterized
+// by namespace/name, for registration from a --suite-config entry.
+// Supported types: cert-manager, capi-system, capa-system, namespace,
+// secret, capi-resources. expectedReplicas is only used by capi-system and
+// capa-system; zero means "require the Deployment's own status.replicas".
+func NewValidatorFromConfig(capi *CAPIValidator, validatorType, namespace, name string, tags []string, expectedReplicas int32) (Validator, error) {
+	switch validatorType {
+	case "cert-manager":
+		return &certManagerValidator{capi: capi, tags: tags}, nil
+	case "capi-system":
+		return &systemValidator{capi: capi, system: "capi", tags: tags, expectedReplicas: expectedReplicas}, nil
+	case "capa-system":
+		return &systemValidator{capi: capi, system: "capa", tags: tags, expectedReplicas: expectedReplicas}, nil
+	case "namespace":
+		if namespace == "" {
+			return nil, fmt.Errorf("validator type %q requires a namespace", validatorType)
+		}
+		return &namespaceValidator{capi: capi, namespace: namespace, tags: tags}, nil
+	case "secret":
+		if namespace == "" || name == "" {
+			return nil, fmt.Errorf("validator type %q requires a namespace and a name", validatorType)
+		}
+		return &secretValidator{capi: capi, namespace: namespace, name: name, tags: tags}, nil
+	case "capi-resources":
+		if namespace == "" {
+			return nil, fmt.Errorf("validator type %q requires a namespace", validatorType)
+		}
+		return &capiResourcesValidator{capi: capi, namespace: namespace, tags: tags}, nil
+	default:
+		return nil, fmt.Errorf("unknown validator type %q", validatorType)
+	}
+}
+
+type certManagerValidator struct {
+	capi *CAPIValidator
+	tags []string
+}
+
+func (v *certManagerValidator) Name() string   { return "cert-manager" }
+func (v *certManagerValidator) Tags() []string { return v.tags }
+func (v *certManagerValidator) Run(_ context.Context) ValidationResult {
+	return v.capi.ValidateCertManager()
+}
+
+// systemValidator checks the CAPI or CAPA controller-manager deployment.
+type systemValidator struct {
+	capi   *CAPIValidator
+	system string // "capi" or "capa"
+	tags   []string
+	// expectedReplicas overrides how many ready replicas the deployment
+	// must have; zero means "require status.replicas".
+	expectedReplicas int32
+}
+
+func (v *systemValidator) Name() string   { return fmt.Sprintf("%s-system", v.system) }
+func (v *systemValidator) Tags() []string { return v.tags }
+func (v *systemValidator) Run(_ context.Context) ValidationResult {
+	if v.system == "capa" {
+		return v.capi.ValidateCAPASystem(v.expectedReplicas)
+	}
+	return v.capi.ValidateCAPISystem(v.expectedReplicas)
+}
+
+type namespaceValidator struct {
+	capi      *CAPIValidator
+	namespace string
+	tags      []string
+}
+
+func (v *namespaceValidator) Name() string   { return fmt.Sprintf("namespace:%s", v.namespace) }
+func (v *namespaceValidator) Tags() []string { return v.tags }
+func (v *namespaceValidator) Run(_ context.Context) ValidationResult {
+	return v.capi.ValidateNamespace(v.namespace)
+}
+
+type secretValidator struct {
+	capi      *CAPIValidator
+	namespace string
+	name      string
+	tags      []string
+}
+
+func (v *secretValidator) Name() string   { return fmt.Sprintf("secret:%s/%s", v.namespace, v.name) }
+func (v *secretValidator) Tags() []string { return v.tags }
+func (v *secretValidator) Run(_ context.Context) ValidationResult {
+	return v.capi.ValidateSecret(v.namespace, v.name)
+}
+
+// capiResourcesValidator wraps ValidateCAPIResources, which returns one
+// ValidationResult per CRD instance found, collapsing them into the single
+// result the Validator interface expects.
+type capiResourcesValidator struct {
+	capi      *CAPIValidator
+	namespace string
+	tags      []string
+}
+
+func (v *capiResourcesValidator) Name() string   { return fmt.Sprintf("capi-resources:%s", v.namespace) }
+func (v *capiResourcesValidator) Tags() []string { return v.tags }
+func (v *capiResourcesValidator) Run(_ context.Context) ValidationResult {
+	start := time.Now()
+	results := v.capi.ValidateCAPIResources(v.namespace)
+
+	failed := 0
+	var firstFailure string
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+			if firstFailure == "" {
+				firstFailure = r.Message
+			}
+		}
+	}
+
+	if failed > 0 {
+		return ValidationResult{
+			Name:      v.Name(),
+			Passed:    false,
+			Message:   fmt.Sprintf("%d/%d CAPI resources in namespace %s not ready: %s", failed, len(results), v.namespace, firstFailure),
+			Timestamp: start,
+			Duration:  time.Since(start).String(),
+			Namespace: v.namespace,
+		}
+	}
+	return ValidationResult{
+		Name:      v.Name(),
+		Passed:    true,
+		Message:   fmt.Sprintf("%d CAPI resources in namespace %s are ready", len(results), v.namespace),
+		Timestamp: start,
+		Duration:  time.Since(start).String(),
+		Namespace: v.namespace,
+	}
+}
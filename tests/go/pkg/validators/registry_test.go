@@ -0,0 +1,57 @@
+package validators
+
+import "testing"
+
+func TestRegistryFilter(t *testing.T) {
+	smoke := &fakeValidator{name: "smoke-check", tags: []string{"smoke"}}
+	rosa := &fakeValidator{name: "rosa-check", tags: []string{"rosa"}}
+	both := &fakeValidator{name: "both-check", tags: []string{"smoke", "rosa"}}
+
+	registry := NewRegistry()
+	registry.Register(smoke)
+	registry.Register(rosa)
+	registry.Register(both)
+
+	if got := registry.Filter(nil); len(got) != 3 {
+		t.Fatalf("Filter(nil) returned %d validators, want 3", len(got))
+	}
+
+	got := registry.Filter([]string{"rosa"})
+	if len(got) != 2 {
+		t.Fatalf("Filter([rosa]) returned %d validators, want 2", len(got))
+	}
+	for _, v := range got {
+		if v.Name() != "rosa-check" && v.Name() != "both-check" {
+			t.Errorf("Filter([rosa]) unexpectedly matched %s", v.Name())
+		}
+	}
+}
+
+func TestNewValidatorFromConfigUnknownType(t *testing.T) {
+	if _, err := NewValidatorFromConfig(nil, "not-a-real-type", "", "", nil, 0); err == nil {
+		t.Fatal("NewValidatorFromConfig() error = nil, want an error for an unknown type")
+	}
+}
+
+func TestNewValidatorFromConfigRequiresNamespace(t *testing.T) {
+	cases := []string{"namespace", "secret", "capi-resources"}
+	for _, typ := range cases {
+		if _, err := NewValidatorFromConfig(nil, typ, "", "name", nil, 0); err == nil {
+			t.Errorf("NewValidatorFromConfig(%q) error = nil, want an error when namespace is missing", typ)
+		}
+	}
+}
+
+func TestNewValidatorFromConfigSystemTypesCarryExpectedReplicas(t *testing.T) {
+	v, err := NewValidatorFromConfig(nil, "capi-system", "", "", []string{"smoke"}, 2)
+	if err != nil {
+		t.Fatalf("NewValidatorFromConfig() error = %v", err)
+	}
+	sv, ok := v.(*systemValidator)
+	if !ok {
+		t.Fatalf("NewValidatorFromConfig() returned %T, want *systemValidator", v)
+	}
+	if sv.expectedReplicas != 2 {
+		t.Errorf("expectedReplicas = %d, want 2", sv.expectedReplicas)
+	}
+}
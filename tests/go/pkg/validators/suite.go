@@ -0,0 +1,147 @@
+package validators
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// SuiteConfig controls which validators a suite run selects and how it
+// polls them: how long each check waits for a CAPI/CAPA deployment to roll
+// out before failing, and how often it re-polls the API server while
+// waiting.
+type SuiteConfig struct {
+	EventuallyTimeout time.Duration
+	PollInterval      time.Duration
+	// JUnitPath, if set, writes a JUnit XML report there in addition to
+	// the JSON report the CLI already produces.
+	JUnitPath string
+	// Tags restricts the run to validators carrying at least one of
+	// these tags. An empty Tags runs every validator in the registry.
+	Tags []string
+}
+
+// DefaultSuiteConfig mirrors the polling cadence used by upstream CAPI e2e
+// suites.
+func DefaultSuiteConfig() SuiteConfig {
+	return SuiteConfig{
+		EventuallyTimeout: 5 * time.Minute,
+		PollInterval:      5 * time.Second,
+	}
+}
+
+// DefaultRegistry returns the registry backing the CLI's default
+// "capi-installation" suite, i.e. the same checks RunAllValidations used to
+// run.
+func DefaultRegistry(capi *CAPIValidator) *Registry {
+	registry := NewRegistry()
+	registry.Register(&certManagerValidator{capi: capi, tags: []string{"smoke"}})
+	registry.Register(&systemValidator{capi: capi, system: "capi", tags: []string{"smoke", "capi"}})
+	registry.Register(&systemValidator{capi: capi, system: "capa", tags: []string{"smoke", "capa"}})
+	registry.Register(&namespaceValidator{capi: capi, namespace: "ns-rosa-hcp", tags: []string{"rosa"}})
+	registry.Register(&capiResourcesValidator{capi: capi, namespace: "ns-rosa-hcp", tags: []string{"rosa"}})
+	return registry
+}
+
+// RunSuite runs registry's validators, filtered by cfg.Tags: each check is
+// polled until it passes or cfg.EventuallyTimeout elapses, so a CAPI
+// installation that's still rolling out doesn't fail on a one-shot Get. It
+// returns one ValidationResult per validator and, when cfg.JUnitPath is
+// set, also writes a JUnit XML report there.
+//
+// This deliberately doesn't drive Ginkgo's ginkgo.RunSpecs: RunSpecs is a
+// process-wide singleton that os.Exit(1)s if it's invoked a second time,
+// which would crash --serve mode (one RunSuite call per tick) and
+// multi-context runs (one RunSuite call per --context) the second time
+// either ran. The poll loop in eventually() is hand-rolled on
+// wait.PollUntilContextTimeout rather than gomega.Eventually for the same
+// reason k8sutil.Retry is: the bare gomega DSL panics unless
+// gomega.RegisterFailHandler/RegisterTestingT has been called somewhere,
+// which nothing in this binary does.
+func RunSuite(cfg SuiteConfig, registry *Registry) []ValidationResult {
+	if cfg.EventuallyTimeout == 0 {
+		cfg.EventuallyTimeout = DefaultSuiteConfig().EventuallyTimeout
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultSuiteConfig().PollInterval
+	}
+
+	toRun := registry.Filter(cfg.Tags)
+
+	results := make([]ValidationResult, 0, len(toRun))
+	for _, val := range toRun {
+		results = append(results, eventually(cfg, val))
+	}
+
+	if cfg.JUnitPath != "" {
+		if err := writeJUnitReport(cfg.JUnitPath, results); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  failed to write JUnit report: %v\n", err)
+		}
+	}
+
+	return results
+}
+
+// eventually polls val every cfg.PollInterval until it passes or
+// cfg.EventuallyTimeout elapses, returning the last observed result. It's
+// built on wait.PollUntilContextTimeout rather than gomega.Eventually so it
+// doesn't depend on gomega.RegisterFailHandler having been called, the same
+// reasoning behind k8sutil.Retry.
+func eventually(cfg SuiteConfig, val Validator) ValidationResult {
+	ctx := context.Background()
+	var last ValidationResult
+	_ = wait.PollUntilContextTimeout(ctx, cfg.PollInterval, cfg.EventuallyTimeout, true, func(context.Context) (bool, error) {
+		last = val.Run(ctx)
+		return last.Passed, nil
+	})
+	return last
+}
+
+// junitTestSuite and junitTestCase are the minimal JUnit XML shape CI
+// dashboards expect; writeJUnitReport fills them in directly from
+// []ValidationResult rather than going through Ginkgo's reporter, since
+// RunSuite no longer drives ginkgo.RunSpecs.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnitReport(path string, results []ValidationResult) error {
+	suite := junitTestSuite{Name: "CAPI Validation Suite", Tests: len(results)}
+	for _, r := range results {
+		tc := junitTestCase{Name: r.Name, Time: r.Duration}
+		if !r.Passed {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.Message, Text: r.Message}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"testing"
+)
+
+type fakeValidator struct {
+	name   string
+	tags   []string
+	result ValidationResult
+}
+
+func (f *fakeValidator) Name() string                           { return f.name }
+func (f *fakeValidator) Tags() []string                         { return f.tags }
+func (f *fakeValidator) Run(_ context.Context) ValidationResult { return f.result }
+
+// TestRunSuiteTwice guards against the Ginkgo-singleton regression this
+// file used to have: ginkgo.RunSpecs can only be called once per process,
+// so a second RunSuite call (as --serve and multi-context runs both make)
+// used to os.Exit(1) the whole binary.
+func TestRunSuiteTwice(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&fakeValidator{name: "always-passes", result: ValidationResult{Name: "always-passes", Passed: true}})
+
+	cfg := SuiteConfig{EventuallyTimeout: 0, PollInterval: 0}
+	for i := 0; i < 2; i++ {
+		results := RunSuite(cfg, registry)
+		if len(results) != 1 || !results[0].Passed {
+			t.Fatalf("run %d: got %+v, want one passing result", i, results)
+		}
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	path := t.TempDir() + "/junit.xml"
+	results := []ValidationResult{
+		{Name: "ok", Passed: true, Duration: "1ms"},
+		{Name: "bad", Passed: false, Message: "boom", Duration: "2ms"},
+	}
+
+	if err := writeJUnitReport(path, results); err != nil {
+		t.Fatalf("writeJUnitReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	var suite junitTestSuite
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("failed to unmarshal JUnit report: %v", err)
+	}
+	if suite.Tests != 2 || suite.Failures != 1 {
+		t.Fatalf("got tests=%d failures=%d, want tests=2 failures=1", suite.Tests, suite.Failures)
+	}
+}